@@ -0,0 +1,67 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// ChunkCodec decodes wire-format bytes into a ChunkData. Whatever wire codec is used, DataHash is
+// always computed over the struct's canonical form (see canonicalCBOR), so a chunk submitted as
+// JSON and later re-verified as CBOR yields the same hash; codecs have no say in the canonical
+// form, so ChunkCodec only needs to describe how to decode the wire bytes.
+type ChunkCodec interface {
+	Decode(data []byte, d *ChunkData) error
+}
+
+// jsonChunkCodec is the original wire codec: chunkData submitted as base64-wrapped JSON. Preserved
+// for backward compatibility.
+type jsonChunkCodec struct{}
+
+func (jsonChunkCodec) Decode(data []byte, d *ChunkData) error {
+	return json.Unmarshal(data, d)
+}
+
+// cborChunkCodec decodes a deterministic CBOR (RFC 8949 Core Deterministic Encoding) wire payload
+type cborChunkCodec struct{}
+
+func (cborChunkCodec) Decode(data []byte, d *ChunkData) error {
+	return cbor.Unmarshal(data, d)
+}
+
+var (
+	jsonCodec ChunkCodec = jsonChunkCodec{}
+	cborCodec ChunkCodec = cborChunkCodec{}
+)
+
+// canonicalEncMode is the shared deterministic (RFC 8949 Core Deterministic Encoding) CBOR
+// encoder used to compute the canonical form of a ChunkData, independently of whichever wire
+// codec decoded it.
+var canonicalEncMode = func() cbor.EncMode {
+	mode, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		panic(fmt.Sprintf("unable to build canonical CBOR encoding mode: %s", err))
+	}
+	return mode
+}()
+
+// canonicalCBOR returns d's canonical, deterministic CBOR encoding
+func canonicalCBOR(d ChunkData) ([]byte, error) {
+	return canonicalEncMode.Marshal(d)
+}
+
+// decodeChunkDataBase64 base64-decodes enc and hands the raw bytes to codec
+func decodeChunkDataBase64(codec ChunkCodec, enc string) (ChunkData, error) {
+	raw, err := base64DecodeRaw(enc)
+	if err != nil {
+		return ChunkData{}, err
+	}
+
+	var chunkData ChunkData
+	if err := codec.Decode(raw, &chunkData); err != nil {
+		return ChunkData{}, err
+	}
+
+	return chunkData, nil
+}
@@ -0,0 +1,51 @@
+package chaincode
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// TestHashCodecIndependence verifies that the same ChunkData, submitted once as base64-wrapped
+// JSON and once as base64-wrapped CBOR, hashes to the same DataHash: the canonical form DataHash
+// is computed over is deliberately independent of whichever wire codec decoded it.
+func TestHashCodecIndependence(t *testing.T) {
+	chunkData := ChunkData{
+		Id:      "codec-independence-sample",
+		Sensor0: SensorData{X: []float64{1, 2, 3}, Y: []float64{0.1, 0.2, 0.3}, Z: []float64{-1, -2, -3}},
+		Sensor1: SensorData{X: []float64{1}, Y: []float64{1}, Z: []float64{1}},
+	}
+
+	jsonBytes, err := json.Marshal(chunkData)
+	if err != nil {
+		t.Fatalf("unable to marshal JSON: %s", err)
+	}
+	cborBytes, err := cbor.Marshal(chunkData)
+	if err != nil {
+		t.Fatalf("unable to marshal CBOR: %s", err)
+	}
+
+	jsonChunkDataDecoded, err := decodeChunkDataBase64(jsonCodec, base64.StdEncoding.EncodeToString(jsonBytes))
+	if err != nil {
+		t.Fatalf("unable to decode JSON input: %s", err)
+	}
+	cborChunkDataDecoded, err := decodeChunkDataBase64(cborCodec, base64.StdEncoding.EncodeToString(cborBytes))
+	if err != nil {
+		t.Fatalf("unable to decode CBOR input: %s", err)
+	}
+
+	jsonHash, err := jsonChunkDataDecoded.hash("")
+	if err != nil {
+		t.Fatalf("unable to hash JSON-decoded chunk: %s", err)
+	}
+	cborHash, err := cborChunkDataDecoded.hash("")
+	if err != nil {
+		t.Fatalf("unable to hash CBOR-decoded chunk: %s", err)
+	}
+
+	if jsonHash != cborHash {
+		t.Errorf("DataHash depends on wire codec: JSON gave %q, CBOR gave %q", jsonHash, cborHash)
+	}
+}
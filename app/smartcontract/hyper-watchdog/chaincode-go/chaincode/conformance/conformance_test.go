@@ -0,0 +1,45 @@
+package conformance
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/n-elia/fabric-benchmarking/app/smartcontract/hyper-watchdog/chaincode-go/chaincode"
+)
+
+// TestPolicyConformance iterates every vector under testdata/vectors/policies and fails if
+// applying its policyId to its chunkData no longer yields expectedExpiryPeriod. Set
+// SKIP_CONFORMANCE to skip this test for short runs.
+func TestPolicyConformance(t *testing.T) {
+	if os.Getenv("SKIP_CONFORMANCE") != "" {
+		t.Skip("SKIP_CONFORMANCE set, skipping policy conformance vectors")
+	}
+
+	vectors, err := LoadVectors("testdata/vectors/policies")
+	if err != nil {
+		t.Fatalf("unable to load conformance vectors: %s", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no conformance vectors found under testdata/vectors/policies")
+	}
+
+	for _, v := range vectors {
+		v := v
+		t.Run(v.PolicyId+"/"+v.ChunkData.Id, func(t *testing.T) {
+			expected, err := time.ParseDuration(v.ExpectedExpiryPeriod)
+			if err != nil {
+				t.Fatalf("invalid expectedExpiryPeriod %q: %s", v.ExpectedExpiryPeriod, err)
+			}
+
+			got, err := chaincode.ApplyPolicy(v.PolicyId, v.ChunkData)
+			if err != nil {
+				t.Fatalf("ApplyPolicy(%q): %s", v.PolicyId, err)
+			}
+
+			if got != expected {
+				t.Errorf("policy %q: expected expiry period %s, got %s", v.PolicyId, expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Command gen regenerates the policy conformance vector corpus from the captured ChunkData
+// samples under testdata/samples, by re-running each sample's policy and writing out the
+// resulting expiry period. Run it from the chaincode-go module root whenever policy logic is
+// intentionally changed:
+//
+//	go run ./chaincode/conformance/gen
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/n-elia/fabric-benchmarking/app/smartcontract/hyper-watchdog/chaincode-go/chaincode/conformance"
+)
+
+const (
+	samplesDir = "chaincode/conformance/testdata/samples"
+	vectorsDir = "chaincode/conformance/testdata/vectors/policies"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	samples, err := conformance.LoadSamples(samplesDir)
+	if err != nil {
+		return fmt.Errorf("unable to load samples: %w", err)
+	}
+
+	for i, sample := range samples {
+		vector, err := conformance.GenerateVector(sample.PolicyId, sample.ChunkData)
+		if err != nil {
+			return fmt.Errorf("sample %d (%s): %w", i, sample.PolicyId, err)
+		}
+
+		out, err := json.MarshalIndent(vector, "", "  ")
+		if err != nil {
+			return fmt.Errorf("sample %d (%s): unable to marshal vector: %w", i, sample.PolicyId, err)
+		}
+
+		dir := filepath.Join(vectorsDir, sample.PolicyId)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("unable to create %s: %w", dir, err)
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("sample-%02d.json", i))
+		if err := os.WriteFile(path, append(out, '\n'), 0o644); err != nil {
+			return fmt.Errorf("unable to write %s: %w", path, err)
+		}
+
+		fmt.Printf("wrote %s\n", path)
+	}
+
+	return nil
+}
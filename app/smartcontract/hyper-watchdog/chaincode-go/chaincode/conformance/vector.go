@@ -0,0 +1,104 @@
+// Package conformance provides a versioned test-vector corpus for the chaincode's policies, so
+// that policy logic (e.g. energyPolicy's magic thresholds) cannot silently drift in behavior.
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/n-elia/fabric-benchmarking/app/smartcontract/hyper-watchdog/chaincode-go/chaincode"
+)
+
+// vectorSchemaVersion is bumped whenever the Vector JSON shape changes
+const vectorSchemaVersion = 1
+
+// Vector is a single policy conformance test vector: applying PolicyId to ChunkData must yield
+// ExpectedExpiryPeriod.
+type Vector struct {
+	SchemaVersion        int                 `json:"schemaVersion"`
+	PolicyId             string              `json:"policyId"`
+	ChunkData            chaincode.ChunkData `json:"chunkData"`
+	ExpectedExpiryPeriod string              `json:"expectedExpiryPeriod"`
+}
+
+// Sample is a captured ChunkData input used by `gen` to (re)generate a Vector for a given policy
+type Sample struct {
+	PolicyId  string              `json:"policyId"`
+	ChunkData chaincode.ChunkData `json:"chunkData"`
+}
+
+// LoadVectors recursively loads every *.json vector file found under root
+func LoadVectors(root string) ([]Vector, error) {
+	var vectors []Vector
+
+	err := walkJSON(root, func(path string, raw []byte) error {
+		var v Vector
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		vectors = append(vectors, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vectors, nil
+}
+
+// LoadSamples recursively loads every *.json sample file found under root
+func LoadSamples(root string) ([]Sample, error) {
+	var samples []Sample
+
+	err := walkJSON(root, func(path string, raw []byte) error {
+		var s Sample
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		samples = append(samples, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+// GenerateVector runs policyId's current logic against chunkData and captures the result as a
+// Vector, for `gen` to write out whenever policy logic is intentionally changed
+func GenerateVector(policyId string, chunkData chaincode.ChunkData) (Vector, error) {
+	expiryPeriod, err := chaincode.ApplyPolicy(policyId, chunkData)
+	if err != nil {
+		return Vector{}, err
+	}
+
+	return Vector{
+		SchemaVersion:        vectorSchemaVersion,
+		PolicyId:             policyId,
+		ChunkData:            chunkData,
+		ExpectedExpiryPeriod: expiryPeriod.String(),
+	}, nil
+}
+
+// walkJSON recursively visits every *.json file under root and invokes fn with its raw contents
+func walkJSON(root string, fn func(path string, raw []byte) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return fn(path, raw)
+	})
+}
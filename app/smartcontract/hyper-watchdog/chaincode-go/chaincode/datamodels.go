@@ -3,35 +3,38 @@ package chaincode
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
-// Data structures for unmarshaling JSON messages
+// Data structures for unmarshaling JSON and CBOR messages
 type SensorData struct {
-	X []float64 `json:"x"`
-	Y []float64 `json:"y"`
-	Z []float64 `json:"z"`
+	X []float64 `json:"x" cbor:"x"`
+	Y []float64 `json:"y" cbor:"y"`
+	Z []float64 `json:"z" cbor:"z"`
 }
 
 type ChunkData struct {
-	Id      string     `json:"id"`
-	Sensor0 SensorData `json:"0"`
-	Sensor1 SensorData `json:"1"`
-	Sensor2 SensorData `json:"2"`
-	Sensor3 SensorData `json:"3"`
-	Sensor4 SensorData `json:"4"`
-	Sensor5 SensorData `json:"5"`
+	Id      string     `json:"id" cbor:"id"`
+	Sensor0 SensorData `json:"0" cbor:"0"`
+	Sensor1 SensorData `json:"1" cbor:"1"`
+	Sensor2 SensorData `json:"2" cbor:"2"`
+	Sensor3 SensorData `json:"3" cbor:"3"`
+	Sensor4 SensorData `json:"4" cbor:"4"`
+	Sensor5 SensorData `json:"5" cbor:"5"`
 }
 
-func (d *ChunkData) hash() (string, error) {
-	// serialize to JSON
-	dataJSON, err := json.Marshal(d)
+// hash computes a self-describing "<alg>:<hex>" digest of d, using alg (defaultHashAlg when
+// empty). The digest is taken over d's canonical CBOR form (see canonicalCBOR), not the wire
+// bytes it was decoded from, so the same ChunkData hashes identically regardless of which
+// ChunkCodec decoded it.
+func (d *ChunkData) hash(alg string) (string, error) {
+	canonical, err := canonicalCBOR(*d)
 	if err != nil {
-		return "", fmt.Errorf("unable to serialize to JSON: %s", err)
+		return "", fmt.Errorf("unable to compute canonical form: %s", err)
 	}
 
-	// compute md5 hash
-	dataHash, err := md5Hash(string(dataJSON))
+	dataHash, err := hashWithAlg(alg, canonical)
 	if err != nil {
 		return "", fmt.Errorf("unable to compute data hash: %s", err)
 	}
@@ -39,6 +42,32 @@ func (d *ChunkData) hash() (string, error) {
 	return dataHash, nil
 }
 
+// verifyHash reports whether d hashes to expectedDigest, using whatever algorithm is embedded in
+// expectedDigest. A digest without an "<alg>:" prefix is a pre-multihash, legacy digest: it was
+// computed as an md5 hash over JSON bytes, before DataHash became self-describing and codec
+// independent, so it is verified against d's JSON encoding instead of its canonical CBOR form.
+func (d *ChunkData) verifyHash(expectedDigest string) (bool, error) {
+	if !strings.Contains(expectedDigest, ":") {
+		dataJSON, err := json.Marshal(d)
+		if err != nil {
+			return false, fmt.Errorf("unable to serialize to JSON: %s", err)
+		}
+		return hashers[legacyHashAlg].Sum(dataJSON) == expectedDigest, nil
+	}
+
+	canonical, err := canonicalCBOR(*d)
+	if err != nil {
+		return false, fmt.Errorf("unable to compute canonical form: %s", err)
+	}
+
+	matches, err := verifyDigest(expectedDigest, canonical)
+	if err != nil {
+		return false, fmt.Errorf("unable to verify data hash: %s", err)
+	}
+
+	return matches, nil
+}
+
 func (d *ChunkData) applyPolicy(p PolicyInterface) (time.Duration, error) {
 	return p.applyToChunkData(*d)
 }
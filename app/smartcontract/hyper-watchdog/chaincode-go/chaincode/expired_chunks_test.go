@@ -0,0 +1,52 @@
+package chaincode
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssetsToCSV(t *testing.T) {
+	asOf := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	assets := []*Asset{
+		{
+			ChunkId:         "chunk-1",
+			AppliedPolicyId: "policy-1",
+			DataHash:        "sha256:abc",
+			ExpiryDate:      time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			ExpiryPeriod:    24 * time.Hour,
+		},
+	}
+
+	got, err := assetsToCSV(assets, asOf)
+	if err != nil {
+		t.Fatalf("assetsToCSV() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("assetsToCSV() produced %d lines, want 2 (header + 1 record): %q", len(lines), got)
+	}
+
+	wantHeader := "ChunkId,AppliedPolicyId,DataHash,ExpiryDate,ExpiryPeriod,AgeDays"
+	if lines[0] != wantHeader {
+		t.Errorf("header = %q, want %q", lines[0], wantHeader)
+	}
+
+	wantRecord := "chunk-1,policy-1,sha256:abc,2024-01-05T00:00:00Z,24h0m0s,5.00"
+	if lines[1] != wantRecord {
+		t.Errorf("record = %q, want %q", lines[1], wantRecord)
+	}
+}
+
+func TestAssetsToCSVEmpty(t *testing.T) {
+	got, err := assetsToCSV(nil, time.Now())
+	if err != nil {
+		t.Fatalf("assetsToCSV() error = %v", err)
+	}
+
+	wantHeader := "ChunkId,AppliedPolicyId,DataHash,ExpiryDate,ExpiryPeriod,AgeDays\n"
+	if got != wantHeader {
+		t.Errorf("assetsToCSV(nil) = %q, want header-only %q", got, wantHeader)
+	}
+}
@@ -0,0 +1,63 @@
+package chaincode
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestZeroPadUnixNanoOrdering(t *testing.T) {
+	times := []time.Time{
+		time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), // pre-1970: negative UnixNano
+		time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Unix(0, 0),
+		time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	encoded := make([]string, len(times))
+	for i, ti := range times {
+		encoded[i] = zeroPadUnixNano(ti)
+	}
+
+	sorted := make([]string, len(encoded))
+	copy(sorted, encoded)
+	sort.Strings(sorted)
+
+	for i := range encoded {
+		if encoded[i] != sorted[i] {
+			t.Fatalf("zeroPadUnixNano encodings are not in chronological order: got %v, want %v", encoded, sorted)
+		}
+	}
+}
+
+func TestZeroPadUnixNanoFixedWidth(t *testing.T) {
+	times := []time.Time{
+		time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Unix(0, 0),
+		time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, ti := range times {
+		if got := len(zeroPadUnixNano(ti)); got != len(expiryIndexMinKey) {
+			t.Errorf("zeroPadUnixNano(%v) has width %d, want %d", ti, got, len(expiryIndexMinKey))
+		}
+	}
+}
+
+func TestIsCompositeKey(t *testing.T) {
+	tests := []struct {
+		key    string
+		expect bool
+	}{
+		{"", false},
+		{"chunk-1", false},
+		{"\x00expiry~date~chunkId\x00...", true},
+	}
+
+	for _, tt := range tests {
+		if got := isCompositeKey(tt.key); got != tt.expect {
+			t.Errorf("isCompositeKey(%q) = %v, want %v", tt.key, got, tt.expect)
+		}
+	}
+}
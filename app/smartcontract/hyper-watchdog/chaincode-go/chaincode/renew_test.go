@@ -0,0 +1,66 @@
+package chaincode
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChunkExpired(t *testing.T) {
+	s := &SmartContract{}
+
+	tests := []struct {
+		name   string
+		asset  Asset
+		expect bool
+	}{
+		{"zero expiry date never expires", Asset{ExpiryDate: time.Time{}}, false},
+		{"future expiry date not expired", Asset{ExpiryDate: time.Now().Add(time.Hour)}, false},
+		{"past expiry date expired", Asset{ExpiryDate: time.Now().Add(-time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.chunkExpired(tt.asset); got != tt.expect {
+				t.Errorf("chunkExpired() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestRenewAnchorUsesNow(t *testing.T) {
+	before := time.Now()
+	anchor, err := renewAnchor(true, func() (time.Time, error) {
+		t.Fatal("getCreationTime should not be called when useNowAsAnchor is set")
+		return time.Time{}, nil
+	})
+	after := time.Now()
+
+	if err != nil {
+		t.Fatalf("renewAnchor() error = %v", err)
+	}
+	if anchor.Before(before) || anchor.After(after) {
+		t.Errorf("renewAnchor() = %v, want a time between %v and %v", anchor, before, after)
+	}
+}
+
+func TestRenewAnchorUsesCreationTime(t *testing.T) {
+	creationTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	anchor, err := renewAnchor(false, func() (time.Time, error) { return creationTime, nil })
+
+	if err != nil {
+		t.Fatalf("renewAnchor() error = %v", err)
+	}
+	if !anchor.Equal(creationTime) {
+		t.Errorf("renewAnchor() = %v, want %v", anchor, creationTime)
+	}
+}
+
+func TestRenewAnchorPropagatesError(t *testing.T) {
+	wantErr := errors.New("history scan failed")
+	_, err := renewAnchor(false, func() (time.Time, error) { return time.Time{}, wantErr })
+
+	if !errors.Is(err, wantErr) {
+		t.Errorf("renewAnchor() error = %v, want %v", err, wantErr)
+	}
+}
@@ -1,14 +1,43 @@
 package chaincode
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
 
+// expiredChunksScanPageSize is the page size GetExpiredChunks uses when walking GetExpiredChunksPage
+const expiredChunksScanPageSize = 100
+
+// expiryIndexName is the secondary composite-key index maintained over ExpiryDate
+const expiryIndexName = "expiry~date~chunkId"
+
+// expiryIndexMinKey is the lower bound of the expiry~date~chunkId key range: zeroPadUnixNano's
+// encoding of math.MinInt64, the earliest representable UnixNano value
+const expiryIndexMinKey = "00000000000000000000"
+
+// zeroPadUnixNano formats t's UnixNano value as a fixed-width, order-preserving decimal string, so
+// that lexicographic ordering of the resulting strings matches chronological ordering of the
+// times, including times before 1970, whose UnixNano is negative
+func zeroPadUnixNano(t time.Time) string {
+	return fmt.Sprintf("%020d", uint64(t.UnixNano())^signBit)
+}
+
+// signBit flips an int64's sign bit so its uint64 reinterpretation sorts the same as the original
+// signed value
+const signBit = uint64(1) << 63
+
+// isCompositeKey reports whether a raw world-state key belongs to a composite-key index
+func isCompositeKey(key string) bool {
+	return len(key) > 0 && key[0] == 0x00
+}
+
 // SmartContract provides functions for managing an Asset
 type SmartContract struct {
 	contractapi.Contract
@@ -40,7 +69,11 @@ func (s *SmartContract) createAsset(ctx contractapi.TransactionContextInterface,
 		return err
 	}
 
-	return ctx.GetStub().PutState(a.ChunkId, assetJSON)
+	if err := ctx.GetStub().PutState(a.ChunkId, assetJSON); err != nil {
+		return err
+	}
+
+	return s.indexAssetExpiry(ctx, nil, &a)
 }
 
 // readAsset reads a given asset from the world state
@@ -68,7 +101,7 @@ func (s *SmartContract) updateAsset(ctx contractapi.TransactionContextInterface,
 		return fmt.Errorf("unable to update asset: %s", "given ID is not bound to given asset")
 	}
 
-	_, err := s.readAsset(ctx, id)
+	previousAsset, err := s.readAsset(ctx, id)
 	if err != nil {
 		return fmt.Errorf("unable to update asset: %s", err)
 	}
@@ -78,20 +111,51 @@ func (s *SmartContract) updateAsset(ctx contractapi.TransactionContextInterface,
 		return err
 	}
 
-	return ctx.GetStub().PutState(id, assetBytes)
+	if err := ctx.GetStub().PutState(id, assetBytes); err != nil {
+		return err
+	}
+
+	return s.indexAssetExpiry(ctx, &previousAsset, &a)
 }
 
 // deleteAsset deletes an given asset from the world state
 func (s *SmartContract) deleteAsset(ctx contractapi.TransactionContextInterface, id string) error {
-	exists, err := s.assetExists(ctx, id)
+	asset, err := s.readAsset(ctx, id)
 	if err != nil {
 		return err
 	}
-	if !exists {
-		return fmt.Errorf("the asset %s does not exist", id)
+
+	if err := ctx.GetStub().DelState(id); err != nil {
+		return err
 	}
 
-	return ctx.GetStub().DelState(id)
+	return s.indexAssetExpiry(ctx, &asset, nil)
+}
+
+// indexAssetExpiry keeps the expiry~date~chunkId secondary index in sync with an asset's
+// ExpiryDate. Either old or new may be nil.
+func (s *SmartContract) indexAssetExpiry(ctx contractapi.TransactionContextInterface, old, new *Asset) error {
+	if old != nil && !old.ExpiryDate.IsZero() {
+		oldKey, err := ctx.GetStub().CreateCompositeKey(expiryIndexName, []string{zeroPadUnixNano(old.ExpiryDate), old.ChunkId})
+		if err != nil {
+			return err
+		}
+		if err := ctx.GetStub().DelState(oldKey); err != nil {
+			return err
+		}
+	}
+
+	if new != nil && !new.ExpiryDate.IsZero() {
+		newKey, err := ctx.GetStub().CreateCompositeKey(expiryIndexName, []string{zeroPadUnixNano(new.ExpiryDate), new.ChunkId})
+		if err != nil {
+			return err
+		}
+		if err := ctx.GetStub().PutState(newKey, []byte{0x00}); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // assetExists returns true when asset with given ID exists in world state
@@ -104,6 +168,60 @@ func (s *SmartContract) assetExists(ctx contractapi.TransactionContextInterface,
 	return assetJSON != nil, nil
 }
 
+// === events
+// ChunkExpiryEvent is emitted whenever a chunk's expiry date is changed by a renew/extend transaction
+type ChunkExpiryEvent struct {
+	ChunkId            string        `json:"ChunkId"`
+	PreviousExpiryDate time.Time     `json:"PreviousExpiryDate"`
+	NewExpiryDate      time.Time     `json:"NewExpiryDate"`
+	Delta              time.Duration `json:"Delta"`
+}
+
+// newExpiryEvent builds the ChunkExpiryEvent describing a change to an asset's expiry date
+func newExpiryEvent(chunkId string, previousExpiryDate, newExpiryDate time.Time) ChunkExpiryEvent {
+	return ChunkExpiryEvent{
+		ChunkId:            chunkId,
+		PreviousExpiryDate: previousExpiryDate,
+		NewExpiryDate:      newExpiryDate,
+		Delta:              newExpiryDate.Sub(previousExpiryDate),
+	}
+}
+
+// emitExpiryEvent emits a ChunkExpiryEvent describing a change to an asset's expiry date
+func (s *SmartContract) emitExpiryEvent(ctx contractapi.TransactionContextInterface, eventName, chunkId string, previousExpiryDate, newExpiryDate time.Time) error {
+	payload, err := json.Marshal(newExpiryEvent(chunkId, previousExpiryDate, newExpiryDate))
+	if err != nil {
+		return fmt.Errorf("unable to marshal event payload: %s", err)
+	}
+
+	return ctx.GetStub().SetEvent(eventName, payload)
+}
+
+// emitExpiryEventBatch emits a single event carrying one ChunkExpiryEvent per renewed chunk
+func (s *SmartContract) emitExpiryEventBatch(ctx contractapi.TransactionContextInterface, eventName string, events []ChunkExpiryEvent) error {
+	payload, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("unable to marshal event payload: %s", err)
+	}
+
+	return ctx.GetStub().SetEvent(eventName, payload)
+}
+
+// chunkExpired returns true if the given asset's expiry date has already been reached or passed
+func (s *SmartContract) chunkExpired(asset Asset) bool {
+	return !asset.ExpiryDate.IsZero() && !asset.ExpiryDate.After(time.Now())
+}
+
+// renewAnchor resolves the anchor time a renewal's new expiry is computed from: time.Now() when
+// useNowAsAnchor is set, otherwise whatever getCreationTime returns. getCreationTime is only
+// called when actually needed, so useNowAsAnchor callers skip the GetHistoryForKey scan.
+func renewAnchor(useNowAsAnchor bool, getCreationTime func() (time.Time, error)) (time.Time, error) {
+	if useNowAsAnchor {
+		return time.Now(), nil
+	}
+	return getCreationTime()
+}
+
 // === private transactions
 // HistoryQueryResult structure used for returning result of history query
 type HistoryQueryResult struct {
@@ -199,8 +317,10 @@ func (s *SmartContract) computeExpiryDate(ctx contractapi.TransactionContextInte
 }
 
 // === public transactions
-// AddChunk creates an asset for the given chunk
-func (s *SmartContract) AddChunk(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64 string) error {
+// addChunk creates an asset for the given chunk, decoding chunkDataBase64 with codec. hashAlg
+// selects the registered Hasher used to compute DataHash (one of "md5", "sha256", "sha3-256",
+// "blake2b-256"); an empty hashAlg defaults to defaultHashAlg.
+func (s *SmartContract) addChunk(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, hashAlg string, codec ChunkCodec) error {
 	exists, err := s.assetExists(ctx, chunkId)
 	if err != nil {
 		return err
@@ -209,15 +329,13 @@ func (s *SmartContract) AddChunk(ctx contractapi.TransactionContextInterface, ch
 		return fmt.Errorf("the asset %s already exists", chunkId)
 	}
 
-	// decode chunkDataBase64, which is a base64 serialized JSON document
-	var chunkData ChunkData
-	err = base64DecodeJson(&chunkData, chunkDataBase64)
+	chunkData, err := decodeChunkDataBase64(codec, chunkDataBase64)
 	if err != nil {
 		return fmt.Errorf("unable to decode base64 input: %s", err)
 	}
 
-	// compute chunk data hash, which is an md5 digest of JSON data chunk
-	chunkDataHash, err := chunkData.hash()
+	// compute the chunk data hash as a self-describing "<alg>:<hex>" digest
+	chunkDataHash, err := chunkData.hash(hashAlg)
 	if err != nil {
 		return fmt.Errorf("unable to compute data hash: %s", err)
 	}
@@ -234,8 +352,21 @@ func (s *SmartContract) AddChunk(ctx contractapi.TransactionContextInterface, ch
 	return s.createAsset(ctx, newAsset)
 }
 
-// AddChunkWithPolicy creates an asset for the given chunk and applies the given policy
-func (s *SmartContract) AddChunkWithPolicy(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, policyId string) error {
+// AddChunk creates an asset for the given chunk, chunkDataBase64 being base64-wrapped JSON
+func (s *SmartContract) AddChunk(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, hashAlg string) error {
+	return s.addChunk(ctx, chunkId, chunkDataBase64, hashAlg, jsonCodec)
+}
+
+// AddChunkCBOR creates an asset for the given chunk, chunkDataCborB64 being base64-wrapped,
+// deterministically-encoded CBOR (RFC 8949 Core Deterministic Encoding)
+func (s *SmartContract) AddChunkCBOR(ctx contractapi.TransactionContextInterface, chunkId, chunkDataCborB64, hashAlg string) error {
+	return s.addChunk(ctx, chunkId, chunkDataCborB64, hashAlg, cborCodec)
+}
+
+// addChunkWithPolicy creates an asset for the given chunk and applies the given policy, decoding
+// chunkDataBase64 with codec. hashAlg selects the registered Hasher used to compute DataHash, as
+// in addChunk.
+func (s *SmartContract) addChunkWithPolicy(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, policyId, hashAlg string, codec ChunkCodec) error {
 	exists, err := s.assetExists(ctx, chunkId)
 	if err != nil {
 		return err
@@ -244,15 +375,13 @@ func (s *SmartContract) AddChunkWithPolicy(ctx contractapi.TransactionContextInt
 		return fmt.Errorf("the asset %s already exists", chunkId)
 	}
 
-	// decode chunkDataBase64, which is a base64 serialized JSON document
-	var chunkData ChunkData
-	err = base64DecodeJson(&chunkData, chunkDataBase64)
+	chunkData, err := decodeChunkDataBase64(codec, chunkDataBase64)
 	if err != nil {
 		return fmt.Errorf("unable to decode base64 input: %s", err)
 	}
 
-	// compute chunk data hash, which is an md5 digest of JSON data chunk
-	chunkDataHash, err := chunkData.hash()
+	// compute the chunk data hash as a self-describing "<alg>:<hex>" digest
+	chunkDataHash, err := chunkData.hash(hashAlg)
 	if err != nil {
 		return fmt.Errorf("unable to compute data hash: %s", err)
 	}
@@ -275,9 +404,23 @@ func (s *SmartContract) AddChunkWithPolicy(ctx contractapi.TransactionContextInt
 	return s.createAsset(ctx, newAsset)
 }
 
-// AddChunkWithPolicyBatch batch creates assets for the given chunks and applies the given policy to them
-func (s *SmartContract) AddChunkWithPolicyBatch(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, policyId []string) error {
-	if len(chunkId) != len(chunkDataBase64) || len(chunkId) != len(policyId) {
+// AddChunkWithPolicy creates an asset for the given chunk and applies the given policy,
+// chunkDataBase64 being base64-wrapped JSON
+func (s *SmartContract) AddChunkWithPolicy(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, policyId, hashAlg string) error {
+	return s.addChunkWithPolicy(ctx, chunkId, chunkDataBase64, policyId, hashAlg, jsonCodec)
+}
+
+// AddChunkWithPolicyCBOR creates an asset for the given chunk and applies the given policy,
+// chunkDataCborB64 being base64-wrapped, deterministically-encoded CBOR
+func (s *SmartContract) AddChunkWithPolicyCBOR(ctx contractapi.TransactionContextInterface, chunkId, chunkDataCborB64, policyId, hashAlg string) error {
+	return s.addChunkWithPolicy(ctx, chunkId, chunkDataCborB64, policyId, hashAlg, cborCodec)
+}
+
+// AddChunkWithPolicyBatch batch creates assets for the given chunks and applies the given policy
+// to them. hashAlg selects, per chunk, the registered Hasher used to compute DataHash, as in
+// AddChunk.
+func (s *SmartContract) AddChunkWithPolicyBatch(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, policyId, hashAlg []string) error {
+	if len(chunkId) != len(chunkDataBase64) || len(chunkId) != len(policyId) || len(chunkId) != len(hashAlg) {
 		return fmt.Errorf("input arrays have different sizes")
 	}
 
@@ -300,8 +443,8 @@ func (s *SmartContract) AddChunkWithPolicyBatch(ctx contractapi.TransactionConte
 			return fmt.Errorf("asset with ID %s: unable to decode base64 input: %s", err, chunkId[i])
 		}
 
-		// compute chunk data hash, which is a md5 digest of JSON data chunk
-		chunkDataHash, err := chunkData.hash()
+		// compute the chunk data hash as a self-describing "<alg>:<hex>" digest
+		chunkDataHash, err := chunkData.hash(hashAlg[i])
 		if err != nil {
 			return fmt.Errorf("asset with ID %s: unable to compute data hash: %s", err, chunkId[i])
 		}
@@ -334,29 +477,25 @@ func (s *SmartContract) AddChunkWithPolicyBatch(ctx contractapi.TransactionConte
 	return nil
 }
 
-// ApplyPolicy applies the given policy to the given asset
-func (s *SmartContract) ApplyPolicy(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, policyId string) error {
+// applyPolicy applies the given policy to the given asset, decoding chunkDataBase64 with codec
+func (s *SmartContract) applyPolicy(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, policyId string, codec ChunkCodec) error {
 	// read the asset from world state
 	asset, err := s.readAsset(ctx, chunkId)
 	if err != nil {
 		return err
 	}
 
-	// decode chunkDataBase64, which is a base64 serialized JSON document
-	var chunkData ChunkData
-	err = base64DecodeJson(&chunkData, chunkDataBase64)
+	chunkData, err := decodeChunkDataBase64(codec, chunkDataBase64)
 	if err != nil {
 		return fmt.Errorf("unable to decode base64 input: %s", err)
 	}
 
-	// compute chunk data hash, which is an md5 digest of JSON data chunk
-	givenDataHash, err := chunkData.hash()
+	// verify the given data against the stored, self-describing DataHash
+	matches, err := chunkData.verifyHash(asset.DataHash)
 	if err != nil {
-		return fmt.Errorf("unable to compute data hash: %s", err)
+		return fmt.Errorf("unable to apply policy: %s", err)
 	}
-
-	// assert that the hash of given data is equal to the stored hash
-	if asset.DataHash != givenDataHash {
+	if !matches {
 		return fmt.Errorf("unable to apply policy: %s", "given data hash differs from stored one")
 	}
 
@@ -380,6 +519,166 @@ func (s *SmartContract) ApplyPolicy(ctx contractapi.TransactionContextInterface,
 	return s.updateAsset(ctx, chunkId, asset)
 }
 
+// ApplyPolicy applies the given policy to the given asset, chunkDataBase64 being base64-wrapped
+// JSON
+func (s *SmartContract) ApplyPolicy(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, policyId string) error {
+	return s.applyPolicy(ctx, chunkId, chunkDataBase64, policyId, jsonCodec)
+}
+
+// ApplyPolicyCBOR applies the given policy to the given asset, chunkDataCborB64 being
+// base64-wrapped, deterministically-encoded CBOR
+func (s *SmartContract) ApplyPolicyCBOR(ctx contractapi.TransactionContextInterface, chunkId, chunkDataCborB64, policyId string) error {
+	return s.applyPolicy(ctx, chunkId, chunkDataCborB64, policyId, cborCodec)
+}
+
+// RenewChunk re-runs a policy against a stored asset and resets its ExpiryPeriod/ExpiryDate
+func (s *SmartContract) RenewChunk(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, newPolicyId string, useNowAsAnchor, force bool) error {
+	// read the asset from world state
+	asset, err := s.readAsset(ctx, chunkId)
+	if err != nil {
+		return err
+	}
+
+	if !force && s.chunkExpired(asset) {
+		return fmt.Errorf("unable to renew chunk: %s", "asset has already expired; pass force=true to override")
+	}
+
+	// decode chunkDataBase64, which is a base64 serialized JSON document
+	var chunkData ChunkData
+	err = base64DecodeJson(&chunkData, chunkDataBase64)
+	if err != nil {
+		return fmt.Errorf("unable to decode base64 input: %s", err)
+	}
+
+	// the asset's DataHash is immutable: verify the given data against the stored digest
+	matches, err := chunkData.verifyHash(asset.DataHash)
+	if err != nil {
+		return fmt.Errorf("unable to renew chunk: %s", err)
+	}
+	if !matches {
+		return fmt.Errorf("unable to renew chunk: %s", "given data hash differs from stored one")
+	}
+
+	// retrieve and apply the new policy
+	newExpiryPeriod, err := chunkData.applyPolicyById(newPolicyId)
+	if err != nil {
+		return fmt.Errorf("unable to apply policy: %s", err)
+	}
+
+	anchor, err := renewAnchor(useNowAsAnchor, func() (time.Time, error) { return s.getAssetCreationTime(ctx, chunkId) })
+	if err != nil {
+		return fmt.Errorf("unable to renew chunk: %s", err)
+	}
+
+	previousExpiryDate := asset.ExpiryDate
+	asset.AppliedPolicyId = newPolicyId
+	asset.ExpiryPeriod = newExpiryPeriod
+	asset.ExpiryDate = anchor.Add(newExpiryPeriod)
+
+	if err := s.updateAsset(ctx, chunkId, asset); err != nil {
+		return err
+	}
+
+	return s.emitExpiryEvent(ctx, "RenewChunk", chunkId, previousExpiryDate, asset.ExpiryDate)
+}
+
+// RenewChunksBatch batch-renews the given chunks, mirroring AddChunkWithPolicyBatch
+func (s *SmartContract) RenewChunksBatch(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64, newPolicyId []string, useNowAsAnchor, force bool) error {
+	if len(chunkId) != len(chunkDataBase64) || len(chunkId) != len(newPolicyId) {
+		return fmt.Errorf("input arrays have different sizes")
+	}
+
+	type renewedAsset struct {
+		asset              Asset
+		previousExpiryDate time.Time
+	}
+	var renewedAssets []renewedAsset
+
+	// Check input values and compute the renewed Assets
+	for i := range chunkId {
+		asset, err := s.readAsset(ctx, chunkId[i])
+		if err != nil {
+			return fmt.Errorf("asset with ID %s: %s", chunkId[i], err)
+		}
+
+		if !force && s.chunkExpired(asset) {
+			return fmt.Errorf("asset with ID %s: unable to renew chunk: %s", chunkId[i], "asset has already expired; pass force=true to override")
+		}
+
+		// decode chunkDataBase64, which is a base64 serialized JSON document
+		var chunkData ChunkData
+		err = base64DecodeJson(&chunkData, chunkDataBase64[i])
+		if err != nil {
+			return fmt.Errorf("asset with ID %s: unable to decode base64 input: %s", chunkId[i], err)
+		}
+
+		// the asset's DataHash is immutable: verify the given data against the stored digest
+		matches, err := chunkData.verifyHash(asset.DataHash)
+		if err != nil {
+			return fmt.Errorf("asset with ID %s: unable to renew chunk: %s", chunkId[i], err)
+		}
+		if !matches {
+			return fmt.Errorf("asset with ID %s: unable to renew chunk: %s", chunkId[i], "given data hash differs from stored one")
+		}
+
+		// retrieve and apply the new policy
+		newExpiryPeriod, err := chunkData.applyPolicyById(newPolicyId[i])
+		if err != nil {
+			return fmt.Errorf("asset with ID %s: unable to apply policy: %s", chunkId[i], err)
+		}
+
+		anchor, err := renewAnchor(useNowAsAnchor, func() (time.Time, error) { return s.getAssetCreationTime(ctx, chunkId[i]) })
+		if err != nil {
+			return fmt.Errorf("asset with ID %s: unable to renew chunk: %s", chunkId[i], err)
+		}
+
+		previousExpiryDate := asset.ExpiryDate
+		asset.AppliedPolicyId = newPolicyId[i]
+		asset.ExpiryPeriod = newExpiryPeriod
+		asset.ExpiryDate = anchor.Add(newExpiryPeriod)
+
+		renewedAssets = append(renewedAssets, renewedAsset{asset: asset, previousExpiryDate: previousExpiryDate})
+	}
+
+	events := make([]ChunkExpiryEvent, 0, len(renewedAssets))
+	for _, r := range renewedAssets {
+		if err := s.updateAsset(ctx, r.asset.ChunkId, r.asset); err != nil {
+			return fmt.Errorf("failed to put asset to world state. %v", err)
+		}
+		events = append(events, newExpiryEvent(r.asset.ChunkId, r.previousExpiryDate, r.asset.ExpiryDate))
+	}
+
+	return s.emitExpiryEventBatch(ctx, "RenewChunk", events)
+}
+
+// ExtendChunkExpiry adds additionalDuration (a time.Duration string, e.g. "24h") to the given
+// asset's current ExpiryDate
+func (s *SmartContract) ExtendChunkExpiry(ctx contractapi.TransactionContextInterface, chunkId, additionalDuration string, force bool) error {
+	asset, err := s.readAsset(ctx, chunkId)
+	if err != nil {
+		return err
+	}
+
+	if !force && s.chunkExpired(asset) {
+		return fmt.Errorf("unable to extend chunk expiry: %s", "asset has already expired; pass force=true to override")
+	}
+
+	delta, err := time.ParseDuration(additionalDuration)
+	if err != nil {
+		return fmt.Errorf("unable to parse additionalDuration: %s", err)
+	}
+
+	previousExpiryDate := asset.ExpiryDate
+	asset.ExpiryDate = asset.ExpiryDate.Add(delta)
+	asset.ExpiryPeriod += delta
+
+	if err := s.updateAsset(ctx, chunkId, asset); err != nil {
+		return err
+	}
+
+	return s.emitExpiryEvent(ctx, "ExtendChunkExpiry", chunkId, previousExpiryDate, asset.ExpiryDate)
+}
+
 // UpdateChunkExpiryDate updates an *existing* asset in the world state with a deterministic expiry time
 func (s *SmartContract) UpdateChunkExpiryDate(ctx contractapi.TransactionContextInterface, id string) error {
 	// read the asset
@@ -408,17 +707,90 @@ func (s *SmartContract) UpdateChunkExpiryDate(ctx contractapi.TransactionContext
 	return s.updateAssetExpiryDate(ctx, id, expiryDate)
 }
 
-// GetExpiredChunks returns all expired assets found in world state
-func (s *SmartContract) GetExpiredChunks(ctx contractapi.TransactionContextInterface, expiryDateRFC3339 string) ([]*Asset, error) {
+// parseExpiryQueryTime parses and validates the expiryDateRFC3339 argument shared by the
+// GetExpiredChunks* queries
+func parseExpiryQueryTime(expiryDateRFC3339 string) (time.Time, error) {
 	givenExpiryTime, err := time.Parse(time.RFC3339, expiryDateRFC3339)
 	if err != nil {
-		return []*Asset{}, fmt.Errorf("unable to parse expiryDatetime. Please use RFC3339 syntax, e.g. %q", "2014-11-12T11:45:26.371Z")
+		return time.Time{}, fmt.Errorf("unable to parse expiryDatetime. Please use RFC3339 syntax, e.g. %q", "2014-11-12T11:45:26.371Z")
 	}
 	if givenExpiryTime.After(time.Now()) {
-		return []*Asset{}, fmt.Errorf("queries to future dates are not allowed.")
+		return time.Time{}, fmt.Errorf("queries to future dates are not allowed.")
 	}
 
-	resultsIterator, err := ctx.GetStub().GetStateByRange("", "")
+	return givenExpiryTime, nil
+}
+
+// ExpiredPage is a single page of a GetExpiredChunksPage / GetExpiredChunksRichQuery query
+type ExpiredPage struct {
+	Assets          []*Asset `json:"Assets"`
+	NextBookmark    string   `json:"NextBookmark"`
+	FetchedBookmark string   `json:"FetchedBookmark"`
+}
+
+// GetExpiredChunksPage returns one page of assets that expired before expiryDateRFC3339, walking
+// the expiry~date~chunkId secondary index. pass the returned NextBookmark back in as bookmark to
+// fetch subsequent pages.
+func (s *SmartContract) GetExpiredChunksPage(ctx contractapi.TransactionContextInterface, expiryDateRFC3339 string, pageSize int32, bookmark string) (*ExpiredPage, error) {
+	givenExpiryTime, err := parseExpiryQueryTime(expiryDateRFC3339)
+	if err != nil {
+		return nil, err
+	}
+
+	startKey, err := ctx.GetStub().CreateCompositeKey(expiryIndexName, []string{expiryIndexMinKey})
+	if err != nil {
+		return nil, err
+	}
+	endKey, err := ctx.GetStub().CreateCompositeKey(expiryIndexName, []string{zeroPadUnixNano(givenExpiryTime)})
+	if err != nil {
+		return nil, err
+	}
+
+	resultsIterator, metadata, err := ctx.GetStub().GetStateByRangeWithPagination(startKey, endKey, pageSize, bookmark)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var assets []*Asset
+	for resultsIterator.HasNext() {
+		indexEntry, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		_, keyParts, err := ctx.GetStub().SplitCompositeKey(indexEntry.Key)
+		if err != nil {
+			return nil, err
+		}
+		chunkId := keyParts[1]
+
+		asset, err := s.readAsset(ctx, chunkId)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, &asset)
+	}
+
+	return &ExpiredPage{
+		Assets:          assets,
+		NextBookmark:    metadata.Bookmark,
+		FetchedBookmark: bookmark,
+	}, nil
+}
+
+// GetExpiredChunksRichQuery is the CouchDB equivalent of GetExpiredChunksPage, using a rich query
+// instead of a range scan. Only usable when the channel is backed by CouchDB.
+func (s *SmartContract) GetExpiredChunksRichQuery(ctx contractapi.TransactionContextInterface, expiryDateRFC3339 string, pageSize int32, bookmark string) (*ExpiredPage, error) {
+	givenExpiryTime, err := parseExpiryQueryTime(expiryDateRFC3339)
+	if err != nil {
+		return nil, err
+	}
+
+	// the "$gt" bound on ExpiryDate excludes assets whose ExpiryDate is still the zero value
+	queryString := fmt.Sprintf(`{"selector":{"ExpiryDate":{"$gt":%q,"$lt":%q},"AppliedPolicyId":{"$ne":""}}}`, time.Time{}.Format(time.RFC3339Nano), givenExpiryTime.Format(time.RFC3339Nano))
+
+	resultsIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(queryString, pageSize, bookmark)
 	if err != nil {
 		return nil, err
 	}
@@ -436,35 +808,85 @@ func (s *SmartContract) GetExpiredChunks(ctx contractapi.TransactionContextInter
 		if err != nil {
 			return nil, err
 		}
+		assets = append(assets, &asset)
+	}
 
-		var count int = 0
-		if (asset.ExpiryDate != time.Time{}) && (asset.ExpiryDate.Before(givenExpiryTime)) { // policy applied, expDate available
-			assets = append(assets, &asset)
-			count += 1
-			if count == 30 {
-				break
-			}
+	return &ExpiredPage{
+		Assets:          assets,
+		NextBookmark:    metadata.Bookmark,
+		FetchedBookmark: bookmark,
+	}, nil
+}
+
+// GetExpiredChunks returns all expired assets found in world state, by walking every GetExpiredChunksPage
+func (s *SmartContract) GetExpiredChunks(ctx contractapi.TransactionContextInterface, expiryDateRFC3339 string) ([]*Asset, error) {
+	var assets []*Asset
+	bookmark := ""
+	for {
+		page, err := s.GetExpiredChunksPage(ctx, expiryDateRFC3339, expiredChunksScanPageSize, bookmark)
+		if err != nil {
+			return nil, err
 		}
-		// Removed because causes timeout errors when a high number of assets have not been processed yet
-		//else if (asset.ExpiryDate == time.Time{}) && (asset.AppliedPolicyId != "") { // policy applied, expDate unavailable
-		//// compute the expiry date
-		//expiryDate, err := s.computeExpiryDate(ctx, asset.ChunkId, asset.ExpiryPeriod)
-		//if err != nil {
-		//	continue
-		//}
-		//// save the expiry date into the asset
-		//err = s.updateAssetExpiryDate(ctx, asset.ChunkId, expiryDate)
-		//if err != nil {
-		//	continue
-		//}
-		//// append the asset to the returned list
-		//assets = append(assets, &asset)
-		//}
+
+		assets = append(assets, page.Assets...)
+
+		if page.NextBookmark == "" {
+			break
+		}
+		bookmark = page.NextBookmark
 	}
 
 	return assets, nil
 }
 
+// assetsToCSV renders assets as a CSV report (ChunkId, AppliedPolicyId, DataHash, ExpiryDate,
+// ExpiryPeriod, AgeDays), with AgeDays measured relative to asOf
+func assetsToCSV(assets []*Asset, asOf time.Time) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{"ChunkId", "AppliedPolicyId", "DataHash", "ExpiryDate", "ExpiryPeriod", "AgeDays"}); err != nil {
+		return "", fmt.Errorf("unable to write CSV header: %s", err)
+	}
+
+	for _, asset := range assets {
+		ageDays := asOf.Sub(asset.ExpiryDate).Hours() / 24
+		record := []string{
+			asset.ChunkId,
+			asset.AppliedPolicyId,
+			asset.DataHash,
+			asset.ExpiryDate.Format(time.RFC3339),
+			asset.ExpiryPeriod.String(),
+			strconv.FormatFloat(ageDays, 'f', 2, 64),
+		}
+		if err := w.Write(record); err != nil {
+			return "", fmt.Errorf("unable to write CSV record for chunk %s: %s", asset.ChunkId, err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("unable to flush CSV writer: %s", err)
+	}
+
+	return buf.String(), nil
+}
+
+// GetExpiredChunksCSV returns a CSV-encoded report of every asset that expired before expiryDateRFC3339
+func (s *SmartContract) GetExpiredChunksCSV(ctx contractapi.TransactionContextInterface, expiryDateRFC3339 string) (string, error) {
+	givenExpiryTime, err := parseExpiryQueryTime(expiryDateRFC3339)
+	if err != nil {
+		return "", err
+	}
+
+	assets, err := s.GetExpiredChunks(ctx, expiryDateRFC3339)
+	if err != nil {
+		return "", err
+	}
+
+	return assetsToCSV(assets, givenExpiryTime)
+}
+
 // DeleteChunkIfExpired deletes the given asset from the world state, only if it has expired
 func (s *SmartContract) DeleteChunkIfExpired(ctx contractapi.TransactionContextInterface, chunkId string) error {
 	// read the asset from world state
@@ -491,6 +913,44 @@ func (s *SmartContract) DeleteChunkIfExpired(ctx contractapi.TransactionContextI
 	return s.deleteAsset(ctx, chunkId)
 }
 
+// RebuildExpiryIndex (re)emits the expiry~date~chunkId composite key for every asset in world
+// state, and returns the number of assets indexed
+func (s *SmartContract) RebuildExpiryIndex(ctx contractapi.TransactionContextInterface) (int, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByRange("", "")
+	if err != nil {
+		return 0, err
+	}
+	defer resultsIterator.Close()
+
+	indexed := 0
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return indexed, err
+		}
+
+		if isCompositeKey(queryResponse.Key) { // skip expiry~date~chunkId index entries
+			continue
+		}
+
+		var asset Asset
+		if err := json.Unmarshal(queryResponse.Value, &asset); err != nil {
+			return indexed, err
+		}
+
+		if asset.ExpiryDate.IsZero() { // indexAssetExpiry writes no key for an unset ExpiryDate
+			continue
+		}
+
+		if err := s.indexAssetExpiry(ctx, nil, &asset); err != nil {
+			return indexed, err
+		}
+		indexed++
+	}
+
+	return indexed, nil
+}
+
 // GetAllAssets returns all assets found in world state
 func (s *SmartContract) GetAllAssets(ctx contractapi.TransactionContextInterface) ([]*Asset, error) {
 	// range query with empty string for startKey and endKey does an
@@ -508,6 +968,10 @@ func (s *SmartContract) GetAllAssets(ctx contractapi.TransactionContextInterface
 			return nil, err
 		}
 
+		if isCompositeKey(queryResponse.Key) { // skip expiry~date~chunkId index entries
+			continue
+		}
+
 		var asset Asset
 		err = json.Unmarshal(queryResponse.Value, &asset)
 		if err != nil {
@@ -537,3 +1001,33 @@ func (s *SmartContract) ChunkExisted(ctx contractapi.TransactionContextInterface
 func (s *SmartContract) ReadChunk(ctx contractapi.TransactionContextInterface, chunkId string) (Asset, error) {
 	return s.readAsset(ctx, chunkId)
 }
+
+// verifyChunk reports whether chunkDataBase64, decoded with codec, still hashes to the given
+// chunk's stored DataHash, re-hashing with whatever algorithm is embedded in the stored digest
+// (including legacy, unprefixed md5 digests), so clients can audit data integrity independently
+// of which wire codec they used to submit it.
+func (s *SmartContract) verifyChunk(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64 string, codec ChunkCodec) (bool, error) {
+	asset, err := s.readAsset(ctx, chunkId)
+	if err != nil {
+		return false, err
+	}
+
+	chunkData, err := decodeChunkDataBase64(codec, chunkDataBase64)
+	if err != nil {
+		return false, fmt.Errorf("unable to decode base64 input: %s", err)
+	}
+
+	return chunkData.verifyHash(asset.DataHash)
+}
+
+// VerifyChunk reports whether chunkDataBase64 (base64-wrapped JSON) still hashes to the given
+// chunk's stored DataHash
+func (s *SmartContract) VerifyChunk(ctx contractapi.TransactionContextInterface, chunkId, chunkDataBase64 string) (bool, error) {
+	return s.verifyChunk(ctx, chunkId, chunkDataBase64, jsonCodec)
+}
+
+// VerifyChunkCBOR reports whether chunkDataCborB64 (base64-wrapped, deterministically-encoded
+// CBOR) still hashes to the given chunk's stored DataHash
+func (s *SmartContract) VerifyChunkCBOR(ctx contractapi.TransactionContextInterface, chunkId, chunkDataCborB64 string) (bool, error) {
+	return s.verifyChunk(ctx, chunkId, chunkDataCborB64, cborCodec)
+}
@@ -2,18 +2,106 @@ package chaincode
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
 )
 
 func base64DecodeJson(v interface{}, enc string) error {
 	return json.NewDecoder(base64.NewDecoder(base64.StdEncoding, strings.NewReader(enc))).Decode(v)
 }
 
-// md5 hash calculator
-func md5Hash(data string) (string, error) {
-	hmd5 := md5.Sum([]byte(data))
-	return fmt.Sprintf("%x", hmd5), nil
+// base64DecodeRaw decodes a base64-wrapped payload to raw bytes, for wire codecs (e.g. CBOR) that
+// decode from a byte slice rather than a JSON stream
+func base64DecodeRaw(enc string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(enc)
+}
+
+// Hasher computes a content digest, returned as a hex string, over raw bytes
+type Hasher interface {
+	Sum(data []byte) string
+}
+
+type hasherFunc func(data []byte) string
+
+func (f hasherFunc) Sum(data []byte) string {
+	return f(data)
+}
+
+// defaultHashAlg is the algorithm used to hash newly submitted chunks when hashAlg is left empty
+const defaultHashAlg = "sha256"
+
+// legacyHashAlg identifies the algorithm implied by a DataHash that carries no "<alg>:" prefix,
+// i.e. an asset written before DataHash became self-describing
+const legacyHashAlg = "md5"
+
+// hashers holds every registered Hasher, keyed by the algorithm name used in a DataHash's
+// "<alg>:<hex>" prefix
+var hashers = map[string]Hasher{
+	"md5": hasherFunc(func(data []byte) string {
+		sum := md5.Sum(data)
+		return hex.EncodeToString(sum[:])
+	}),
+	"sha256": hasherFunc(func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}),
+	"sha3-256": hasherFunc(func(data []byte) string {
+		sum := sha3.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}),
+	"blake2b-256": hasherFunc(func(data []byte) string {
+		sum := blake2b.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}),
+}
+
+// getHasher returns the Hasher registered under alg, defaulting to defaultHashAlg when alg is
+// empty, along with the resolved algorithm name
+func getHasher(alg string) (Hasher, string, error) {
+	if alg == "" {
+		alg = defaultHashAlg
+	}
+
+	h, ok := hashers[alg]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported hash algorithm %q", alg)
+	}
+
+	return h, alg, nil
+}
+
+// hashWithAlg computes a self-describing "<alg>:<hex>" digest of data using alg, defaulting to
+// defaultHashAlg when alg is empty
+func hashWithAlg(alg string, data []byte) (string, error) {
+	h, alg, err := getHasher(alg)
+	if err != nil {
+		return "", err
+	}
+
+	return alg + ":" + h.Sum(data), nil
+}
+
+// verifyDigest re-hashes data with whatever algorithm is embedded in a self-describing
+// "<alg>:<hex>" digest and reports whether it matches. Legacy, unprefixed digests are handled by
+// ChunkData.verifyHash instead, since they are hashed over a different byte representation.
+func verifyDigest(digest string, data []byte) (bool, error) {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 {
+		return false, fmt.Errorf("malformed digest %q: expected \"<alg>:<hex>\" format", digest)
+	}
+	alg, wantHex := parts[0], parts[1]
+
+	h, ok := hashers[alg]
+	if !ok {
+		return false, fmt.Errorf("unsupported hash algorithm %q", alg)
+	}
+
+	return h.Sum(data) == wantHex, nil
 }